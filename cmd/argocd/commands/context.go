@@ -1,14 +1,18 @@
 package commands
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
 	"os"
 	"path"
 	"strings"
 	"text/tabwriter"
 
+	"github.com/pkg/diff"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
 
 	argocdclient "github.com/argoproj/argo-cd/v2/pkg/apiclient"
 	"github.com/argoproj/argo-cd/v2/util/errors"
@@ -97,10 +101,142 @@ argocd context cd.argoproj.io --delete`,
 		},
 	}
 
+	// Rename subcommand to rename a context
+	renameCommand := &cobra.Command{
+		Use:   "rename OLD NEW",
+		Short: "Rename an Argo CD context",
+		Args:  cobra.ExactArgs(2),
+		Run: func(c *cobra.Command, args []string) {
+			err := renameContext(args[0], args[1], clientOpts.ConfigPath)
+			errors.CheckError(err)
+			fmt.Printf("Context '%s' renamed to '%s'\n", args[0], args[1])
+		},
+	}
+
+	// Set subcommand to create or update a context without logging in
+	var (
+		setServer    string
+		setUser      string
+		setAuthToken string
+		setPlainText bool
+		setGRPCWeb   bool
+		setInsecure  bool
+	)
+	setCommand := &cobra.Command{
+		Use:   "set NAME",
+		Short: "Create or update an Argo CD context without logging in",
+		Args:  cobra.ExactArgs(1),
+		Run: func(c *cobra.Command, args []string) {
+			err := setContext(setContextOpts{
+				name:      args[0],
+				server:    setServer,
+				user:      setUser,
+				authToken: setAuthToken,
+				plainText: setPlainText,
+				grpcWeb:   setGRPCWeb,
+				insecure:  setInsecure,
+			}, clientOpts.ConfigPath)
+			errors.CheckError(err)
+			fmt.Printf("Context '%s' set\n", args[0])
+		},
+	}
+	setCommand.Flags().StringVar(&setServer, "server", "", "Argo CD server for the context")
+	setCommand.Flags().StringVar(&setUser, "user", "", "User to associate with the context (defaults to NAME)")
+	setCommand.Flags().StringVar(&setAuthToken, "auth-token", "", "Authentication token for the context's user")
+	setCommand.Flags().BoolVar(&setPlainText, "plain-text", false, "Disable TLS when connecting to the server")
+	setCommand.Flags().BoolVar(&setGRPCWeb, "grpc-web", false, "Use gRPC-Web protocol when connecting to the server")
+	setCommand.Flags().BoolVar(&setInsecure, "insecure", false, "Skip server certificate verification")
+
+	// Credential subcommand to manage the credential helper
+	credentialCommand := &cobra.Command{
+		Use:   "credential",
+		Short: "Manage the credential helper used to store context auth tokens",
+	}
+	setHelperCommand := &cobra.Command{
+		Use:   "set-helper NAME",
+		Short: "Configure the credential helper used to store auth tokens (e.g. 'keychain')",
+		Long: `Configure the credential helper used to store auth tokens.
+
+Once a credential helper is set, auth-token and refresh-token fields are no longer written
+to the local config file. Instead they are stored and retrieved via "keychain" (the OS
+native secret store) or an "argocd-credential-NAME" binary on PATH.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(c *cobra.Command, args []string) {
+			err := setCredentialHelper(args[0], clientOpts.ConfigPath)
+			errors.CheckError(err)
+			fmt.Printf("Credential helper set to '%s'\n", args[0])
+		},
+	}
+	credentialCommand.AddCommand(setHelperCommand)
+
+	// Current subcommand to print only the current context name, for scripting
+	currentCommand := &cobra.Command{
+		Use:   "current",
+		Short: "Print the current Argo CD context",
+		Args:  cobra.NoArgs,
+		Run: func(c *cobra.Command, args []string) {
+			err := printCurrentContext(clientOpts.ConfigPath)
+			errors.CheckError(err)
+		},
+	}
+
+	// Export subcommand to write one or more contexts to a self-contained config file
+	var exportOut string
+	exportCommand := &cobra.Command{
+		Use:   "export [NAME...]",
+		Short: "Export Argo CD contexts to a self-contained config file",
+		Long: `Export Argo CD contexts to a self-contained config file that bundles each
+context together with its server and user entries, so it can be handed to another
+developer and used directly with "argocd context import" or "--config".
+
+If no context names are given, every context in the local config is exported.`,
+		Run: func(c *cobra.Command, args []string) {
+			err := exportContexts(args, exportOut, clientOpts.ConfigPath)
+			errors.CheckError(err)
+		},
+	}
+	exportCommand.Flags().StringVarP(&exportOut, "out", "o", "", "File to write the exported config to (required)")
+
+	// Import subcommand to merge a config fragment produced by "export" into the local config
+	var (
+		importDryRun bool
+		importDiff   bool
+		importForce  bool
+	)
+	importCommand := &cobra.Command{
+		Use:   "import SOURCE",
+		Short: "Import Argo CD contexts from a config file produced by \"context export\"",
+		Long: `Import Argo CD contexts from a config file produced by "argocd context export",
+merging them into the local config.
+
+By default, a unified diff of the local config before and after the import is printed and
+the user is prompted to confirm before anything is written. --dry-run prints the diff and
+exits without writing or prompting. A context whose name already exists locally aborts the
+import unless --force is passed, in which case it is overwritten.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(c *cobra.Command, args []string) {
+			err := importContexts(args[0], importOpts{
+				dryRun: importDryRun,
+				diff:   importDiff,
+				force:  importForce,
+			}, clientOpts.ConfigPath)
+			errors.CheckError(err)
+		},
+	}
+	importCommand.Flags().BoolVar(&importDryRun, "dry-run", false, "Print the diff without writing anything")
+	importCommand.Flags().BoolVar(&importDiff, "diff", true, "Print a diff of the local config before and after the import")
+	importCommand.Flags().BoolVar(&importForce, "force", false, "Overwrite contexts that already exist locally, and skip the confirmation prompt")
+
 	// Add subcommands to the main command
 	command.AddCommand(listCommand)
 	command.AddCommand(useCommand)
 	command.AddCommand(deleteCommand)
+	command.AddCommand(renameCommand)
+	command.AddCommand(setCommand)
+	command.AddCommand(credentialCommand)
+	command.AddCommand(currentCommand)
+	command.AddCommand(exportCommand)
+	command.AddCommand(importCommand)
 
 	// Add the delete flag for backward compatibility
 	command.Flags().BoolVar(&deleteFlag, "delete", false, "Delete the context instead of switching to it")
@@ -161,6 +297,12 @@ func deleteContext(context, configPath string) error {
 		return fmt.Errorf("nothing to logout from")
 	}
 
+	if localCfg.CredentialHelper != "" {
+		if err := localconfig.GetCredentialHelper(localCfg.CredentialHelper).Erase(context); err != nil {
+			log.Warnf("failed to erase stored credential for context '%s': %v", context, err)
+		}
+	}
+
 	serverName, ok := localCfg.RemoveContext(context)
 	if !ok {
 		return fmt.Errorf("Context %s does not exist", context)
@@ -186,6 +328,246 @@ func deleteContext(context, configPath string) error {
 	return nil
 }
 
+func renameContext(oldName, newName, configPath string) error {
+	localCfg, err := localconfig.ReadLocalConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if localCfg == nil {
+		return fmt.Errorf("nothing to rename, %s is empty", configPath)
+	}
+	if err := localCfg.RenameContext(oldName, newName); err != nil {
+		return err
+	}
+	return localconfig.WriteLocalConfig(*localCfg, configPath)
+}
+
+// setContextOpts holds the parameters accepted by `argocd context set`
+type setContextOpts struct {
+	name      string
+	server    string
+	user      string
+	authToken string
+	plainText bool
+	grpcWeb   bool
+	insecure  bool
+}
+
+// setContext creates or updates a context entry directly, without requiring an interactive
+// `argocd login`. This lets contexts be scripted, e.g. from a CI secret.
+func setContext(opts setContextOpts, configPath string) error {
+	if opts.server == "" {
+		return fmt.Errorf("--server is required")
+	}
+	localCfg, err := localconfig.ReadLocalConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if localCfg == nil {
+		localCfg = &localconfig.LocalConfig{}
+	}
+
+	userName := opts.user
+	if userName == "" {
+		userName = opts.name
+	}
+
+	localCfg.UpsertServer(localconfig.Server{
+		Server:    opts.server,
+		PlainText: opts.plainText,
+		GRPCWeb:   opts.grpcWeb,
+		Insecure:  opts.insecure,
+	})
+	localCfg.UpsertUser(localconfig.User{
+		Name:      userName,
+		AuthToken: opts.authToken,
+	})
+	localCfg.UpsertContext(localconfig.ContextRef{
+		Name:   opts.name,
+		Server: opts.server,
+		User:   userName,
+	})
+
+	if err := localconfig.ValidateLocalConfig(*localCfg); err != nil {
+		return err
+	}
+	return localconfig.WriteLocalConfig(*localCfg, configPath)
+}
+
+// setCredentialHelper configures the credential helper used to store auth tokens. The
+// next write of the local config will push existing tokens to the helper and strip them
+// from the on-disk YAML.
+func setCredentialHelper(name, configPath string) error {
+	localCfg, err := localconfig.ReadLocalConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if localCfg == nil {
+		return fmt.Errorf("nothing to configure, %s is empty", configPath)
+	}
+	localCfg.CredentialHelper = name
+	return localconfig.WriteLocalConfig(*localCfg, configPath)
+}
+
+// exportContexts writes the named contexts -- or, if names is empty, every context in the
+// local config -- to outPath as a self-contained config fragment: each context along with
+// its own copy of its server and user entries, with no current-context set.
+func exportContexts(names []string, outPath, configPath string) error {
+	if outPath == "" {
+		return fmt.Errorf("-o/--out is required")
+	}
+	localCfg, err := localconfig.ReadLocalConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if localCfg == nil {
+		return fmt.Errorf("nothing to export, %s is empty", configPath)
+	}
+	if len(names) == 0 {
+		for _, ctx := range localCfg.Contexts {
+			names = append(names, ctx.Name)
+		}
+	}
+
+	fragment := &localconfig.LocalConfig{}
+	for _, name := range names {
+		ctx, err := localCfg.ResolveContext(name)
+		if err != nil {
+			return err
+		}
+		fragment.UpsertServer(ctx.Server)
+		fragment.UpsertUser(ctx.User)
+		fragment.UpsertContext(localconfig.ContextRef{Name: ctx.Name, Server: ctx.Server.Server, User: ctx.User.Name})
+	}
+
+	if err := localconfig.WriteLocalConfig(*fragment, outPath); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d context(s) to '%s'\n", len(names), outPath)
+	return nil
+}
+
+// importOpts holds the parameters accepted by `argocd context import`
+type importOpts struct {
+	dryRun bool
+	diff   bool
+	force  bool
+}
+
+// redactedForDiff returns a copy of cfg with every user's auth-token and refresh-token
+// cleared, for use in output that isn't the actual written config -- a diff is meant to
+// show what's changing structurally, not to print credentials to the terminal.
+func redactedForDiff(cfg localconfig.LocalConfig) localconfig.LocalConfig {
+	cfg.Users = append([]localconfig.User{}, cfg.Users...)
+	for i := range cfg.Users {
+		cfg.Users[i].AuthToken = ""
+		cfg.Users[i].RefreshToken = ""
+	}
+	return cfg
+}
+
+// importContexts merges the contexts, servers and users defined in the config fragment at
+// sourcePath into the local config, printing a diff and prompting for confirmation along
+// the way per opts.
+func importContexts(sourcePath string, opts importOpts, configPath string) error {
+	data, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return err
+	}
+	var fragment localconfig.LocalConfig
+	if err := yaml.Unmarshal(data, &fragment); err != nil {
+		return fmt.Errorf("parsing %s: %w", sourcePath, err)
+	}
+
+	before, err := localconfig.ReadLocalConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		before = &localconfig.LocalConfig{}
+	}
+	// ReadLocalConfig hydrates auth/refresh tokens into memory for a config with a
+	// credential helper configured, even though those tokens are never written to its
+	// on-disk YAML. Redact them here too, so they don't end up printed to stdout in a diff.
+	preYAML, err := yaml.Marshal(redactedForDiff(*before))
+	if err != nil {
+		return err
+	}
+
+	after := *before
+	after.Contexts = append([]localconfig.ContextRef{}, before.Contexts...)
+	after.Servers = append([]localconfig.Server{}, before.Servers...)
+	after.Users = append([]localconfig.User{}, before.Users...)
+
+	for _, ctx := range fragment.Contexts {
+		if _, err := before.ResolveContext(ctx.Name); err == nil && !opts.force {
+			return fmt.Errorf("context '%s' already exists (use --force to overwrite)", ctx.Name)
+		}
+		server, err := fragment.GetServer(ctx.Server)
+		if err != nil {
+			return fmt.Errorf("context '%s': %w", ctx.Name, err)
+		}
+		user, err := fragment.GetUser(ctx.User)
+		if err != nil {
+			return fmt.Errorf("context '%s': %w", ctx.Name, err)
+		}
+		after.UpsertServer(*server)
+		after.UpsertUser(*user)
+		after.UpsertContext(ctx)
+	}
+
+	if err := localconfig.ValidateLocalConfig(after); err != nil {
+		return err
+	}
+	// A context fragment produced by "context export" can itself carry a plaintext
+	// auth-token for the user it bundles, so redact the "after" snapshot too.
+	postYAML, err := yaml.Marshal(redactedForDiff(after))
+	if err != nil {
+		return err
+	}
+
+	if opts.diff || opts.dryRun {
+		if err := diff.Text(configPath, sourcePath, bytes.NewReader(preYAML), bytes.NewReader(postYAML), os.Stdout); err != nil {
+			return err
+		}
+	}
+	if opts.dryRun {
+		return nil
+	}
+	if !opts.force && !promptConfirm("Apply these changes?") {
+		fmt.Println("Import aborted")
+		return nil
+	}
+
+	if err := localconfig.WriteLocalConfig(after, configPath); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d context(s) from '%s'\n", len(fragment.Contexts), sourcePath)
+	return nil
+}
+
+// promptConfirm asks the user a yes/no question on stdout/stdin, defaulting to "no".
+func promptConfirm(message string) bool {
+	fmt.Printf("%s [y/N] ", message)
+	reply, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	reply = strings.ToLower(strings.TrimSpace(reply))
+	return reply == "y" || reply == "yes"
+}
+
+// printCurrentContext prints only localCfg.CurrentContext, with no other decoration, so it
+// can be captured by scripts the way `kubectl config current-context` can.
+func printCurrentContext(configPath string) error {
+	localCfg, err := localconfig.ReadLocalConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if localCfg == nil || localCfg.CurrentContext == "" {
+		return fmt.Errorf("no current context set")
+	}
+	fmt.Println(localCfg.CurrentContext)
+	return nil
+}
+
 func printArgoCDContexts(configPath string) {
 	localCfg, err := localconfig.ReadLocalConfig(configPath)
 	errors.CheckError(err)
@@ -194,7 +576,7 @@ func printArgoCDContexts(configPath string) {
 	}
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	defer func() { _ = w.Flush() }()
-	columnNames := []string{"CURRENT", "NAME", "SERVER"}
+	columnNames := []string{"CURRENT", "NAME", "SERVER", "CONFIG"}
 	_, err = fmt.Fprintf(w, "%s\n", strings.Join(columnNames, "\t"))
 	errors.CheckError(err)
 
@@ -207,7 +589,7 @@ func printArgoCDContexts(configPath string) {
 		if localCfg.CurrentContext == context.Name {
 			prefix = "*"
 		}
-		_, err = fmt.Fprintf(w, "%s\t%s\t%s\n", prefix, context.Name, context.Server.Server)
+		_, err = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", prefix, context.Name, context.Server.Server, localCfg.ContextConfigPath(context.Name))
 		errors.CheckError(err)
 	}
 }
@@ -107,6 +107,67 @@ func TestPrintArgoCDContexts(t *testing.T) {
 	assert.Contains(t, output, "\targocd2.example.com:443\targocd2.example.com:443")
 }
 
+func TestContextRename(t *testing.T) {
+	// Setup test configuration file
+	err := os.WriteFile(testConfigFilePath, []byte(testConfig), os.ModePerm)
+	require.NoError(t, err)
+	defer os.Remove(testConfigFilePath)
+
+	// Rename the current context
+	err = renameContext("localhost:8080", "local", testConfigFilePath)
+	require.NoError(t, err)
+
+	localConfig, err := localconfig.ReadLocalConfig(testConfigFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, "local", localConfig.CurrentContext)
+	assert.Contains(t, localConfig.Contexts, localconfig.ContextRef{Name: "local", Server: "localhost:8080", User: "local"})
+	assert.NotContains(t, localConfig.Contexts, localconfig.ContextRef{Name: "localhost:8080", Server: "localhost:8080", User: "localhost:8080"})
+
+	// Renaming to a name that already exists is an error
+	err = renameContext("local", "argocd1.example.com:443", testConfigFilePath)
+	require.Error(t, err)
+
+	// Renaming a context that does not exist is an error
+	err = renameContext("does-not-exist", "whatever", testConfigFilePath)
+	require.Error(t, err)
+}
+
+func TestContextSet(t *testing.T) {
+	// Setup test configuration file
+	err := os.WriteFile(testConfigFilePath, []byte(testConfig), os.ModePerm)
+	require.NoError(t, err)
+	defer os.Remove(testConfigFilePath)
+
+	// Add a brand new context without going through `argocd login`
+	err = setContext(setContextOpts{
+		name:      "ci",
+		server:    "ci.example.com:443",
+		authToken: "ci-token",
+		plainText: true,
+	}, testConfigFilePath)
+	require.NoError(t, err)
+
+	localConfig, err := localconfig.ReadLocalConfig(testConfigFilePath)
+	require.NoError(t, err)
+	assert.Contains(t, localConfig.Contexts, localconfig.ContextRef{Name: "ci", Server: "ci.example.com:443", User: "ci"})
+	assert.Contains(t, localConfig.Servers, localconfig.Server{Server: "ci.example.com:443", PlainText: true})
+	assert.Contains(t, localConfig.Users, localconfig.User{Name: "ci", AuthToken: "ci-token"})
+
+	// Calling set again for the same name updates the existing entry in place
+	err = setContext(setContextOpts{
+		name:      "ci",
+		server:    "ci.example.com:443",
+		authToken: "ci-token-2",
+		plainText: true,
+	}, testConfigFilePath)
+	require.NoError(t, err)
+
+	localConfig, err = localconfig.ReadLocalConfig(testConfigFilePath)
+	require.NoError(t, err)
+	assert.Contains(t, localConfig.Users, localconfig.User{Name: "ci", AuthToken: "ci-token-2"})
+	assert.Len(t, localConfig.Contexts, 4)
+}
+
 // Test for useArgoCDContext
 func TestUseArgoCDContext(t *testing.T) {
 	// Setup test configuration file
@@ -130,3 +191,154 @@ func TestUseArgoCDContext(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "localhost:8080", localConfig.CurrentContext)
 }
+
+func TestExportContexts(t *testing.T) {
+	err := os.WriteFile(testConfigFilePath, []byte(testConfig), os.ModePerm)
+	require.NoError(t, err)
+	defer os.Remove(testConfigFilePath)
+
+	exportPath := "./testdata/exported.config"
+	defer os.Remove(exportPath)
+
+	// Export a single, named context
+	err = exportContexts([]string{"argocd1.example.com:443"}, exportPath, testConfigFilePath)
+	require.NoError(t, err)
+
+	exported, err := localconfig.ReadLocalConfig(exportPath)
+	require.NoError(t, err)
+	assert.Equal(t, "", exported.CurrentContext)
+	assert.Len(t, exported.Contexts, 1)
+	assert.Contains(t, exported.Contexts, localconfig.ContextRef{Name: "argocd1.example.com:443", Server: "argocd1.example.com:443", User: "argocd1.example.com:443"})
+	assert.Contains(t, exported.Users, localconfig.User{Name: "argocd1.example.com:443", AuthToken: "vErrYS3c3tReFRe$hToken", RefreshToken: "vErrYS3c3tReFRe$hToken"})
+
+	// Exporting with no names bundles every context
+	err = exportContexts(nil, exportPath, testConfigFilePath)
+	require.NoError(t, err)
+	exported, err = localconfig.ReadLocalConfig(exportPath)
+	require.NoError(t, err)
+	assert.Len(t, exported.Contexts, 3)
+
+	// -o is required
+	err = exportContexts(nil, "", testConfigFilePath)
+	require.Error(t, err)
+}
+
+func TestImportContexts(t *testing.T) {
+	err := os.WriteFile(testConfigFilePath, []byte(testConfig), os.ModePerm)
+	require.NoError(t, err)
+	defer os.Remove(testConfigFilePath)
+
+	bundlePath := "./testdata/bundle.config"
+	defer os.Remove(bundlePath)
+	err = os.WriteFile(bundlePath, []byte(`contexts:
+- name: ci.example.com:443
+  server: ci.example.com:443
+  user: ci.example.com:443
+servers:
+- server: ci.example.com:443
+users:
+- name: ci.example.com:443
+  auth-token: ci-token`), os.ModePerm)
+	require.NoError(t, err)
+
+	// --dry-run never writes the config
+	err = importContexts(bundlePath, importOpts{dryRun: true}, testConfigFilePath)
+	require.NoError(t, err)
+	localConfig, err := localconfig.ReadLocalConfig(testConfigFilePath)
+	require.NoError(t, err)
+	assert.NotContains(t, localConfig.Contexts, localconfig.ContextRef{Name: "ci.example.com:443", Server: "ci.example.com:443", User: "ci.example.com:443"})
+
+	// --force skips the confirmation prompt and writes the new context
+	err = importContexts(bundlePath, importOpts{force: true}, testConfigFilePath)
+	require.NoError(t, err)
+	localConfig, err = localconfig.ReadLocalConfig(testConfigFilePath)
+	require.NoError(t, err)
+	assert.Contains(t, localConfig.Contexts, localconfig.ContextRef{Name: "ci.example.com:443", Server: "ci.example.com:443", User: "ci.example.com:443"})
+	assert.Contains(t, localConfig.Users, localconfig.User{Name: "ci.example.com:443", AuthToken: "ci-token"})
+
+	// Importing a context that already exists aborts without --force
+	err = importContexts(bundlePath, importOpts{force: false}, testConfigFilePath)
+	require.Error(t, err)
+}
+
+func TestImportContexts_DiffRedactsTokens(t *testing.T) {
+	err := os.WriteFile(testConfigFilePath, []byte(testConfig), os.ModePerm)
+	require.NoError(t, err)
+	defer os.Remove(testConfigFilePath)
+
+	bundlePath := "./testdata/bundle.config"
+	defer os.Remove(bundlePath)
+	err = os.WriteFile(bundlePath, []byte(`contexts:
+- name: ci.example.com:443
+  server: ci.example.com:443
+  user: ci.example.com:443
+servers:
+- server: ci.example.com:443
+users:
+- name: ci.example.com:443
+  auth-token: ci-token`), os.ModePerm)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	origStdout := os.Stdout
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout = w
+
+	err = importContexts(bundlePath, importOpts{dryRun: true, diff: true}, testConfigFilePath)
+
+	w.Close()
+	os.Stdout = origStdout
+	_, readErr := buf.ReadFrom(r)
+	require.NoError(t, readErr)
+	require.NoError(t, err)
+
+	output := buf.String()
+	assert.NotContains(t, output, "vErrYS3c3tReFRe$hToken")
+	assert.NotContains(t, output, "ci-token")
+	// The diff should still show the new context being added
+	assert.Contains(t, output, "ci.example.com:443")
+}
+
+// Test for printCurrentContext
+func TestPrintCurrentContext(t *testing.T) {
+	// Setup test configuration file
+	err := os.WriteFile(testConfigFilePath, []byte(testConfig), os.ModePerm)
+	require.NoError(t, err)
+	defer os.Remove(testConfigFilePath)
+
+	// Redirect os.Stdout to capture the output
+	var buf bytes.Buffer
+	origStdout := os.Stdout
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout = w
+
+	err = printCurrentContext(testConfigFilePath)
+
+	w.Close()
+	os.Stdout = origStdout
+	require.NoError(t, err)
+	_, err = buf.ReadFrom(r)
+	require.NoError(t, err)
+
+	assert.Equal(t, "localhost:8080\n", buf.String())
+}
+
+func TestResolveContextName(t *testing.T) {
+	localCfg := &localconfig.LocalConfig{CurrentContext: "localhost:8080"}
+
+	// The flag wins over everything else
+	assert.Equal(t, "from-flag", localconfig.ResolveContextName("from-flag", localCfg))
+
+	// The environment variable wins over current-context
+	t.Setenv(localconfig.ArgoCDContextEnvVar, "from-env")
+	assert.Equal(t, "from-env", localconfig.ResolveContextName("", localCfg))
+
+	// With neither set, current-context from the config applies
+	t.Setenv(localconfig.ArgoCDContextEnvVar, "")
+	assert.Equal(t, "localhost:8080", localconfig.ResolveContextName("", localCfg))
+
+	// A nil config with nothing else set resolves to the empty string
+	assert.Equal(t, "", localconfig.ResolveContextName("", nil))
+}
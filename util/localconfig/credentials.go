@@ -0,0 +1,104 @@
+package localconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// CredentialPayload is the JSON document exchanged with a credential helper, keyed by
+// context name, on the "get", "store" and "erase" verbs.
+type CredentialPayload struct {
+	Context      string `json:"context"`
+	AuthToken    string `json:"auth-token,omitempty"`
+	RefreshToken string `json:"refresh-token,omitempty"`
+}
+
+// CredentialHelper stores and retrieves context credentials outside of the local config
+// file, modeled on Docker's credsStore.
+type CredentialHelper interface {
+	// Get returns the stored credential for context, or a CredentialPayload with only
+	// Context set (empty tokens) if nothing has been stored for it yet.
+	Get(context string) (*CredentialPayload, error)
+	// Store saves (or overwrites) the credential for payload.Context.
+	Store(payload CredentialPayload) error
+	// Erase removes any stored credential for context.
+	Erase(context string) error
+	// List returns the names of all contexts with a stored credential.
+	List() ([]string, error)
+}
+
+// GetCredentialHelper returns the CredentialHelper implementation for name. "keychain"
+// selects the built-in OS keychain helper; any other name is assumed to be the suffix of
+// an `argocd-credential-<name>` binary on PATH.
+func GetCredentialHelper(name string) CredentialHelper {
+	if name == "keychain" {
+		return keychainCredentialHelper{}
+	}
+	return execCredentialHelper{name: name}
+}
+
+// execCredentialHelper shells out to an `argocd-credential-<name>` binary, passing a verb
+// ("get", "store", "erase", "list") as its sole argument and a JSON CredentialPayload on
+// stdin, reading a JSON response from stdout.
+type execCredentialHelper struct {
+	name string
+}
+
+func (h execCredentialHelper) binary() string {
+	return "argocd-credential-" + h.name
+}
+
+func (h execCredentialHelper) run(verb string, payload any) ([]byte, error) {
+	in, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(h.binary(), verb)
+	cmd.Stdin = bytes.NewReader(in)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s %s: %w", h.binary(), verb, err)
+	}
+	return out.Bytes(), nil
+}
+
+func (h execCredentialHelper) Get(context string) (*CredentialPayload, error) {
+	out, err := h.run("get", CredentialPayload{Context: context})
+	if err != nil {
+		return nil, err
+	}
+	payload := CredentialPayload{Context: context}
+	if len(bytes.TrimSpace(out)) > 0 {
+		if err := json.Unmarshal(out, &payload); err != nil {
+			return nil, fmt.Errorf("%s get: %w", h.binary(), err)
+		}
+	}
+	return &payload, nil
+}
+
+func (h execCredentialHelper) Store(payload CredentialPayload) error {
+	_, err := h.run("store", payload)
+	return err
+}
+
+func (h execCredentialHelper) Erase(context string) error {
+	_, err := h.run("erase", CredentialPayload{Context: context})
+	return err
+}
+
+func (h execCredentialHelper) List() ([]string, error) {
+	out, err := h.run("list", struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	if err := json.Unmarshal(out, &names); err != nil {
+		return nil, fmt.Errorf("%s list: %w", h.binary(), err)
+	}
+	return names, nil
+}
@@ -0,0 +1,107 @@
+package localconfig
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// installFakeCredentialHelper writes a tiny store/get/erase helper script backed by a
+// plain file on disk, and prepends its directory to PATH so execCredentialHelper can find
+// it as "argocd-credential-fake".
+func installFakeCredentialHelper(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake credential helper script is POSIX shell only")
+	}
+	// This fake helper only ever stores credentials for a single context, which is all
+	// that TestReadWriteLocalConfig_CredentialHelperStripsTokens needs, so it can just
+	// treat its backing file as an opaque blob rather than parsing out the context key.
+	dir := t.TempDir()
+	store := filepath.Join(dir, "store.json")
+	script := `#!/bin/sh
+set -e
+case "$1" in
+  store) cat > "` + store + `" ;;
+  get) [ -f "` + store + `" ] && cat "` + store + `" || echo '{}' ;;
+  erase) rm -f "` + store + `" ;;
+esac
+`
+	helperPath := filepath.Join(dir, "argocd-credential-fake")
+	require.NoError(t, os.WriteFile(helperPath, []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// installFailingCredentialHelper writes a helper whose "get" verb always exits non-zero,
+// simulating a context with nothing stored (or a helper that's temporarily unreachable).
+func installFailingCredentialHelper(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake credential helper script is POSIX shell only")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\n[ \"$1\" = get ] && exit 1\nexit 0\n"
+	helperPath := filepath.Join(dir, "argocd-credential-failing")
+	require.NoError(t, os.WriteFile(helperPath, []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestReadLocalConfig_CredentialHelperGetFailureIsNonFatal(t *testing.T) {
+	installFailingCredentialHelper(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	require.NoError(t, os.WriteFile(path, []byte(`current-context: localhost:8080
+contexts:
+- name: localhost:8080
+  server: localhost:8080
+  user: localhost:8080
+servers:
+- server: localhost:8080
+users:
+- name: localhost:8080
+credential-helper: failing`), 0o600))
+
+	// A context the helper has nothing stored for must not fail the whole read -- read-only
+	// operations like "context list" should still work for every other context.
+	cfg, err := ReadLocalConfig(path)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	assert.Equal(t, "localhost:8080", cfg.CurrentContext)
+}
+
+func TestReadWriteLocalConfig_CredentialHelperStripsTokens(t *testing.T) {
+	installFakeCredentialHelper(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	require.NoError(t, os.WriteFile(path, []byte(`current-context: localhost:8080
+contexts:
+- name: localhost:8080
+  server: localhost:8080
+  user: localhost:8080
+servers:
+- server: localhost:8080
+users:
+- name: localhost:8080
+  auth-token: secret-token`), 0o600))
+
+	cfg, err := ReadLocalConfig(path)
+	require.NoError(t, err)
+	cfg.CredentialHelper = "fake"
+	require.NoError(t, WriteLocalConfig(*cfg, path))
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "secret-token")
+
+	reread, err := ReadLocalConfig(path)
+	require.NoError(t, err)
+	user, err := reread.GetUser("localhost:8080")
+	require.NoError(t, err)
+	assert.Equal(t, "secret-token", user.AuthToken)
+}
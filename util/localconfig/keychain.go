@@ -0,0 +1,53 @@
+package localconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keychainService is the go-keyring "service" namespace Argo CD stores credentials
+// under: macOS Keychain, Windows Credential Manager, and libsecret each group entries by
+// service name.
+const keychainService = "argocd-cli"
+
+// keychainCredentialHelper is the built-in CredentialHelper backed by the OS-native
+// secret store, selected via `credential-helper: keychain`.
+type keychainCredentialHelper struct{}
+
+func (keychainCredentialHelper) Get(context string) (*CredentialPayload, error) {
+	data, err := keyring.Get(keychainService, context)
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return &CredentialPayload{Context: context}, nil
+		}
+		return nil, err
+	}
+	payload := CredentialPayload{}
+	if err := json.Unmarshal([]byte(data), &payload); err != nil {
+		return nil, fmt.Errorf("keychain: %w", err)
+	}
+	return &payload, nil
+}
+
+func (keychainCredentialHelper) Store(payload CredentialPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return keyring.Set(keychainService, payload.Context, string(data))
+}
+
+func (keychainCredentialHelper) Erase(context string) error {
+	err := keyring.Delete(keychainService, context)
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (keychainCredentialHelper) List() ([]string, error) {
+	return nil, fmt.Errorf("the keychain credential helper does not support listing contexts")
+}
@@ -0,0 +1,593 @@
+package localconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mitchellh/go-homedir"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultLocalConfigPath returns the local configuration path for the Argo CD config
+var DefaultLocalConfigPath = ""
+
+const (
+	// DefaultConfigDirName is the default name of the directory holding the local config
+	DefaultConfigDirName = "argocd"
+	// DefaultConfigFileName is the default name of the local config file
+	DefaultConfigFileName = "config"
+)
+
+// LocalConfig is a local Argo CD config file
+type LocalConfig struct {
+	CurrentContext string       `yaml:"current-context"`
+	Contexts       []ContextRef `yaml:"contexts"`
+	Servers        []Server     `yaml:"servers"`
+	Users          []User       `yaml:"users"`
+	// CredentialHelper, if set, names a credential helper (e.g. "keychain", or any
+	// installed `argocd-credential-<name>` binary) used to store and retrieve
+	// auth-token/refresh-token values instead of writing them to this file. See
+	// GetCredentialHelper.
+	CredentialHelper string `yaml:"credential-helper,omitempty"`
+
+	// paths records, for a config assembled from multiple files (see SplitConfigPath),
+	// which file each context/server/user entry was first read from. It is not
+	// serialized, and is nil for configs that were not produced by ReadLocalConfig.
+	paths map[string]string `yaml:"-"`
+}
+
+// ContextRef is a reference to a Server and User for an Argo CD Context
+type ContextRef struct {
+	Name   string `yaml:"name"`
+	Server string `yaml:"server"`
+	User   string `yaml:"user"`
+}
+
+// Context is a resolved ContextRef, with its Server and User fully dereferenced
+type Context struct {
+	Name   string
+	Server Server
+	User   User
+}
+
+// User is an Argo CD user
+type User struct {
+	Name         string `yaml:"name"`
+	AuthToken    string `yaml:"auth-token,omitempty"`
+	RefreshToken string `yaml:"refresh-token,omitempty"`
+}
+
+// Server contains Argo CD server information
+type Server struct {
+	Server    string `yaml:"server"`
+	PlainText bool   `yaml:"plain-text,omitempty"`
+	Insecure  bool   `yaml:"insecure,omitempty"`
+	GRPCWeb   bool   `yaml:"grpc-web,omitempty"`
+}
+
+// DefaultConfigDir returns the local configuration directory for the Argo CD config
+func DefaultConfigDir() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", DefaultConfigDirName), nil
+}
+
+// DefaultLocalConfigFile returns the default location of the Argo CD config file
+func DefaultLocalConfigFile() (string, error) {
+	dir, err := DefaultConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, DefaultConfigFileName), nil
+}
+
+// SplitConfigPath splits a KUBECONFIG-style config path -- a list of files joined by the
+// OS path list separator (':' on Unix, ';' on Windows) -- into its individual file paths.
+// A single, unadorned path is returned as a one-element slice.
+func SplitConfigPath(configPath string) []string {
+	if configPath == "" {
+		return []string{""}
+	}
+	paths := filepath.SplitList(configPath)
+	if len(paths) == 0 {
+		return []string{configPath}
+	}
+	return paths
+}
+
+// ReadLocalConfig loads up the local configuration file(s). configPath may name a single
+// file or, like KUBECONFIG, a list of files separated by the OS path list separator, in
+// which case they are deep-merged in precedence order: the first file to define a given
+// context/server/user entry wins, and current-context is taken from the first file that
+// sets it. Returns nil if none of the files exist.
+func ReadLocalConfig(configPath string) (*LocalConfig, error) {
+	merged := &LocalConfig{paths: map[string]string{}}
+	var found bool
+	for _, path := range SplitConfigPath(configPath) {
+		if !fileExists(path) {
+			continue
+		}
+		found = true
+		config := LocalConfig{}
+		if err := unmarshalLocalFile(path, &config); err != nil {
+			return nil, err
+		}
+		mergeLocalConfig(merged, &config, path)
+	}
+	if !found {
+		return nil, nil
+	}
+	if err := ValidateLocalConfig(*merged); err != nil {
+		return nil, fmt.Errorf("error validating %s: %w", configPath, err)
+	}
+	if merged.CredentialHelper != "" {
+		if err := hydrateCredentials(merged); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
+
+// hydrateCredentials fills in auth-token/refresh-token for every user referenced by a
+// context by asking cfg.CredentialHelper for the tokens it has stored for that context,
+// since those fields are stripped from disk when a credential helper is configured. A
+// context the helper has nothing stored for (e.g. one that was never logged in, or whose
+// entry predates the helper being configured) is left with empty tokens rather than
+// failing the whole read -- ReadLocalConfig is used for read-only operations like `context
+// list` that should still work for every other context.
+func hydrateCredentials(cfg *LocalConfig) error {
+	helper := GetCredentialHelper(cfg.CredentialHelper)
+	for _, ctx := range cfg.Contexts {
+		payload, err := helper.Get(ctx.Name)
+		if err != nil {
+			log.Warnf("credential helper %s: no stored credential for context '%s': %v", cfg.CredentialHelper, ctx.Name, err)
+			continue
+		}
+		for i, u := range cfg.Users {
+			if u.Name == ctx.User {
+				cfg.Users[i].AuthToken = payload.AuthToken
+				cfg.Users[i].RefreshToken = payload.RefreshToken
+			}
+		}
+	}
+	return nil
+}
+
+// mergeLocalConfig merges src, read from path, into dst, keeping whatever dst already has
+// (i.e. entries from files earlier in the list win) and recording provenance for entries
+// contributed by src.
+func mergeLocalConfig(dst, src *LocalConfig, path string) {
+	if dst.CurrentContext == "" {
+		dst.CurrentContext = src.CurrentContext
+	}
+	if dst.CredentialHelper == "" {
+		dst.CredentialHelper = src.CredentialHelper
+	}
+	for _, ctx := range src.Contexts {
+		key := "context:" + ctx.Name
+		if _, exists := dst.paths[key]; exists {
+			continue
+		}
+		dst.Contexts = append(dst.Contexts, ctx)
+		dst.paths[key] = path
+	}
+	for _, s := range src.Servers {
+		key := "server:" + s.Server
+		if _, exists := dst.paths[key]; exists {
+			continue
+		}
+		dst.Servers = append(dst.Servers, s)
+		dst.paths[key] = path
+	}
+	for _, u := range src.Users {
+		key := "user:" + u.Name
+		if _, exists := dst.paths[key]; exists {
+			continue
+		}
+		dst.Users = append(dst.Users, u)
+		dst.paths[key] = path
+	}
+}
+
+// ContextConfigPath returns the file that the named context was read from, when the
+// config was produced by ReadLocalConfig. It returns "" for configs assembled in-process
+// (e.g. not yet written) or for contexts it has no provenance for.
+func (l *LocalConfig) ContextConfigPath(name string) string {
+	if l == nil || l.paths == nil {
+		return ""
+	}
+	return l.paths["context:"+name]
+}
+
+func unmarshalLocalFile(path string, config *LocalConfig) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(b, config)
+}
+
+func fileExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// WriteLocalConfig writes the configuration back to configPath. When configPath names
+// multiple files (see SplitConfigPath), each context/server/user entry is routed back to
+// the file it was originally read from (per the provenance ReadLocalConfig recorded for it,
+// as exposed for contexts by ContextConfigPath); entries with no known provenance -- i.e. newly added in this process --
+// are written to the first file in the list, which is also where current-context is
+// always stored.
+func WriteLocalConfig(config LocalConfig, configPath string) error {
+	paths := SplitConfigPath(configPath)
+	if len(paths) <= 1 {
+		return writeLocalConfigFile(config, paths[0])
+	}
+
+	primary := paths[0]
+	perFile := make(map[string]*LocalConfig, len(paths))
+	for _, p := range paths {
+		// Every file needs its own copy of CredentialHelper, not just the primary: each
+		// is written independently via writeLocalConfigFile, which only strips auth/refresh
+		// tokens for a file's own Users when that file's CredentialHelper is set.
+		perFile[p] = &LocalConfig{CredentialHelper: config.CredentialHelper}
+	}
+
+	routeTo := func(key string) string {
+		if p := config.paths[key]; p != "" {
+			if _, tracked := perFile[p]; tracked {
+				return p
+			}
+		}
+		return primary
+	}
+
+	for _, ctx := range config.Contexts {
+		p := routeTo("context:" + ctx.Name)
+		perFile[p].Contexts = append(perFile[p].Contexts, ctx)
+	}
+	for _, s := range config.Servers {
+		p := routeTo("server:" + s.Server)
+		perFile[p].Servers = append(perFile[p].Servers, s)
+	}
+	for _, u := range config.Users {
+		p := routeTo("user:" + u.Name)
+		perFile[p].Users = append(perFile[p].Users, u)
+	}
+	perFile[primary].CurrentContext = config.CurrentContext
+
+	for _, p := range paths {
+		fileCfg := perFile[p]
+		if fileCfg.IsEmpty() && fileCfg.CurrentContext == "" {
+			continue
+		}
+		if err := writeLocalConfigFile(*fileCfg, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeLocalConfigFile marshals config and writes it to path via a temp file plus an
+// atomic rename, so a process that dies or is killed mid-write leaves the existing file
+// untouched rather than a truncated or partially-written one.
+func writeLocalConfigFile(config LocalConfig, path string) error {
+	if config.CredentialHelper != "" {
+		if err := storeCredentials(&config); err != nil {
+			return err
+		}
+	}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once the rename below succeeds
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// ArgoCDContextEnvVar is the environment variable consulted by ResolveContextName to pick
+// a context for a single invocation, below an explicit --context flag but above
+// current-context in the config.
+const ArgoCDContextEnvVar = "ARGOCD_CONTEXT"
+
+// ResolveContextName returns the name of the context a single command invocation should
+// use, given the value of an explicit --context flag (may be empty). Precedence, highest
+// first: flagContext, the ARGOCD_CONTEXT environment variable, and current-context from
+// localCfg. This lets one invocation target a non-current context (e.g. `argocd app list
+// --context prod`) without persistently switching the way `argocd context use` does.
+// localCfg may be nil, in which case only flagContext and the environment variable apply.
+func ResolveContextName(flagContext string, localCfg *LocalConfig) string {
+	if flagContext != "" {
+		return flagContext
+	}
+	if envContext := os.Getenv(ArgoCDContextEnvVar); envContext != "" {
+		return envContext
+	}
+	if localCfg == nil {
+		return ""
+	}
+	return localCfg.CurrentContext
+}
+
+// DeleteLocalConfig deletes the local configuration file(s) named by configPath
+func DeleteLocalConfig(configPath string) error {
+	for _, path := range SplitConfigPath(configPath) {
+		if fileExists(path) {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateLocalConfig checks the contents of the local config to make sure its valid
+func ValidateLocalConfig(config LocalConfig) error {
+	if config.CurrentContext != "" {
+		if _, err := config.ResolveContext(config.CurrentContext); err != nil {
+			return fmt.Errorf("current-context: %w", err)
+		}
+	}
+	for _, ctx := range config.Contexts {
+		if _, err := config.ResolveContext(ctx.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResolveContext resolves the specified context and returns the fully dereferenced Context
+func (l *LocalConfig) ResolveContext(name string) (*Context, error) {
+	for _, ctx := range l.Contexts {
+		if ctx.Name == name {
+			server, err := l.GetServer(ctx.Server)
+			if err != nil {
+				return nil, err
+			}
+			user, err := l.GetUser(ctx.User)
+			if err != nil {
+				return nil, err
+			}
+			return &Context{Name: ctx.Name, Server: *server, User: *user}, nil
+		}
+	}
+	return nil, fmt.Errorf("Context '%s' undefined", name)
+}
+
+// GetServer returns a Server by name
+func (l *LocalConfig) GetServer(name string) (*Server, error) {
+	for _, s := range l.Servers {
+		if s.Server == name {
+			return &s, nil
+		}
+	}
+	return nil, fmt.Errorf("Server '%s' undefined", name)
+}
+
+// GetUser returns a User by name
+func (l *LocalConfig) GetUser(name string) (*User, error) {
+	for _, u := range l.Users {
+		if u.Name == name {
+			return &u, nil
+		}
+	}
+	return nil, fmt.Errorf("User '%s' undefined", name)
+}
+
+// UpsertServer updates or inserts the specified Server
+func (l *LocalConfig) UpsertServer(server Server) {
+	for i, s := range l.Servers {
+		if s.Server == server.Server {
+			l.Servers[i] = server
+			return
+		}
+	}
+	l.Servers = append(l.Servers, server)
+}
+
+// UpsertUser updates or inserts the specified User
+func (l *LocalConfig) UpsertUser(user User) {
+	for i, u := range l.Users {
+		if u.Name == user.Name {
+			l.Users[i] = user
+			return
+		}
+	}
+	l.Users = append(l.Users, user)
+}
+
+// UpsertContext updates or inserts the specified ContextRef. It returns true if a new
+// context was added, or false if an existing context of the same name was updated.
+func (l *LocalConfig) UpsertContext(context ContextRef) bool {
+	for i, c := range l.Contexts {
+		if c.Name == context.Name {
+			l.Contexts[i] = context
+			return false
+		}
+	}
+	l.Contexts = append(l.Contexts, context)
+	return true
+}
+
+// RenameContext renames the context named oldName to newName, along with its underlying
+// user entry when that entry is named oldName and no other context uses it (see below),
+// and updates current-context if it was pointing at oldName. The server entry is left
+// untouched since it may be shared by other contexts.
+func (l *LocalConfig) RenameContext(oldName, newName string) error {
+	if oldName == newName {
+		return nil
+	}
+	if _, err := l.ResolveContext(oldName); err != nil {
+		return err
+	}
+	for _, c := range l.Contexts {
+		if c.Name == newName {
+			return fmt.Errorf("Context '%s' already exists", newName)
+		}
+	}
+
+	// `context set --user` lets multiple contexts share one user entry, so the user entry
+	// is only renamed along with the context if the context actually owns it (its User
+	// field equals oldName) and no other context also references oldName as its user --
+	// otherwise renaming it here would break every other context still pointing at it.
+	renameUser := false
+	for _, c := range l.Contexts {
+		if c.Name == oldName && c.User == oldName {
+			renameUser = true
+			break
+		}
+	}
+	if renameUser {
+		for _, c := range l.Contexts {
+			if c.Name != oldName && c.User == oldName {
+				renameUser = false
+				break
+			}
+		}
+	}
+	if renameUser {
+		for _, u := range l.Users {
+			if u.Name == newName {
+				return fmt.Errorf("User '%s' already exists", newName)
+			}
+		}
+	}
+
+	for i, c := range l.Contexts {
+		if c.Name == oldName {
+			l.Contexts[i].Name = newName
+			if renameUser {
+				l.Contexts[i].User = newName
+			}
+			break
+		}
+	}
+	l.renamePathKey("context:"+oldName, "context:"+newName)
+
+	if renameUser {
+		for i, u := range l.Users {
+			if u.Name == oldName {
+				l.Users[i].Name = newName
+				l.renamePathKey("user:"+oldName, "user:"+newName)
+				break
+			}
+		}
+	}
+	if l.CurrentContext == oldName {
+		l.CurrentContext = newName
+	}
+	return nil
+}
+
+// renamePathKey moves the provenance entry recorded under oldKey (if any) to newKey, so
+// that a rename of the in-memory context/server/user it describes doesn't strand its
+// origin-file lookup under a key that no longer matches anything.
+func (l *LocalConfig) renamePathKey(oldKey, newKey string) {
+	if l.paths == nil {
+		return
+	}
+	if p, ok := l.paths[oldKey]; ok {
+		delete(l.paths, oldKey)
+		l.paths[newKey] = p
+	}
+}
+
+// RemoveContext removes the specified context from the config and returns the name of the
+// Server the context referenced (so the caller can decide whether to also remove it)
+func (l *LocalConfig) RemoveContext(name string) (string, bool) {
+	for i, ctx := range l.Contexts {
+		if ctx.Name == name {
+			l.Contexts = append(l.Contexts[:i], l.Contexts[i+1:]...)
+			return ctx.Server, true
+		}
+	}
+	return "", false
+}
+
+// RemoveUser removes the specified user from the config
+func (l *LocalConfig) RemoveUser(name string) bool {
+	for i, u := range l.Users {
+		if u.Name == name {
+			l.Users = append(l.Users[:i], l.Users[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveServer removes the specified server from the config, so long as no remaining
+// context still references it
+func (l *LocalConfig) RemoveServer(name string) bool {
+	for _, ctx := range l.Contexts {
+		if ctx.Server == name {
+			return false
+		}
+	}
+	for i, s := range l.Servers {
+		if s.Server == name {
+			l.Servers = append(l.Servers[:i], l.Servers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// storeCredentials pushes every context's auth-token/refresh-token to config's credential
+// helper, then replaces config.Users with a copy that has those fields cleared, so the
+// caller's in-memory config (which shares the original Users slice) is left untouched and
+// only the stripped copy is marshaled to disk.
+func storeCredentials(config *LocalConfig) error {
+	helper := GetCredentialHelper(config.CredentialHelper)
+	for _, ctx := range config.Contexts {
+		user, err := config.GetUser(ctx.User)
+		if err != nil {
+			continue
+		}
+		err = helper.Store(CredentialPayload{
+			Context:      ctx.Name,
+			AuthToken:    user.AuthToken,
+			RefreshToken: user.RefreshToken,
+		})
+		if err != nil {
+			return fmt.Errorf("credential helper %s: %w", config.CredentialHelper, err)
+		}
+	}
+	stripped := make([]User, len(config.Users))
+	for i, u := range config.Users {
+		stripped[i] = User{Name: u.Name}
+	}
+	config.Users = stripped
+	return nil
+}
+
+// IsEmpty returns true if the config is empty
+func (l *LocalConfig) IsEmpty() bool {
+	return l == nil || (len(l.Contexts) == 0 && len(l.Servers) == 0 && len(l.Users) == 0)
+}
@@ -0,0 +1,126 @@
+package localconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const orgConfig = `current-context: shared.example.com:443
+contexts:
+- name: shared.example.com:443
+  server: shared.example.com:443
+  user: shared.example.com:443
+servers:
+- server: shared.example.com:443
+users:
+- name: shared.example.com:443
+  auth-token: org-issued-token`
+
+const personalConfig = `current-context: localhost:8080
+contexts:
+- name: localhost:8080
+  server: localhost:8080
+  user: localhost:8080
+servers:
+- plain-text: true
+  server: localhost:8080
+users:
+- name: localhost:8080
+  auth-token: personal-token`
+
+func writeTemp(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	return path
+}
+
+func TestReadLocalConfig_MultiFileMerge(t *testing.T) {
+	dir := t.TempDir()
+	personalPath := writeTemp(t, dir, "personal.yaml", personalConfig)
+	orgPath := writeTemp(t, dir, "org.yaml", orgConfig)
+
+	merged, err := ReadLocalConfig(personalPath + string(os.PathListSeparator) + orgPath)
+	require.NoError(t, err)
+	require.NotNil(t, merged)
+
+	// The first file in the list wins for current-context and for duplicate entries
+	assert.Equal(t, "localhost:8080", merged.CurrentContext)
+	assert.Contains(t, merged.Contexts, ContextRef{Name: "localhost:8080", Server: "localhost:8080", User: "localhost:8080"})
+	assert.Contains(t, merged.Contexts, ContextRef{Name: "shared.example.com:443", Server: "shared.example.com:443", User: "shared.example.com:443"})
+
+	assert.Equal(t, personalPath, merged.ContextConfigPath("localhost:8080"))
+	assert.Equal(t, orgPath, merged.ContextConfigPath("shared.example.com:443"))
+}
+
+func TestRenameContext_SharedUserNotRenamedWhileStillReferenced(t *testing.T) {
+	cfg := &LocalConfig{
+		Contexts: []ContextRef{
+			{Name: "foo", Server: "foo", User: "foo"},
+			{Name: "bar", Server: "bar", User: "foo"},
+		},
+		Servers: []Server{{Server: "foo"}, {Server: "bar"}},
+		Users:   []User{{Name: "foo"}},
+	}
+
+	require.NoError(t, cfg.RenameContext("foo", "foo2"))
+
+	// "bar" still points at the shared user "foo", so the user entry must not be renamed
+	_, err := cfg.ResolveContext("bar")
+	require.NoError(t, err)
+
+	ctx, err := cfg.ResolveContext("foo2")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", ctx.User.Name)
+}
+
+func TestWriteLocalConfig_RoutesBackToOrigin(t *testing.T) {
+	dir := t.TempDir()
+	personalPath := writeTemp(t, dir, "personal.yaml", personalConfig)
+	orgPath := writeTemp(t, dir, "org.yaml", orgConfig)
+	configPath := personalPath + string(os.PathListSeparator) + orgPath
+
+	merged, err := ReadLocalConfig(configPath)
+	require.NoError(t, err)
+
+	// Rename the org-provided context; the rewrite should land back in org.yaml, not personal.yaml
+	require.NoError(t, merged.RenameContext("shared.example.com:443", "shared-renamed"))
+	require.NoError(t, WriteLocalConfig(*merged, configPath))
+
+	orgOnDisk, err := ReadLocalConfig(orgPath)
+	require.NoError(t, err)
+	assert.Contains(t, orgOnDisk.Contexts, ContextRef{Name: "shared-renamed", Server: "shared.example.com:443", User: "shared-renamed"})
+
+	personalOnDisk, err := ReadLocalConfig(personalPath)
+	require.NoError(t, err)
+	assert.Contains(t, personalOnDisk.Contexts, ContextRef{Name: "localhost:8080", Server: "localhost:8080", User: "localhost:8080"})
+	assert.NotContains(t, personalOnDisk.Contexts, ContextRef{Name: "shared-renamed", Server: "shared.example.com:443", User: "shared-renamed"})
+}
+
+func TestWriteLocalConfig_MultiFileStripsTokensEverywhere(t *testing.T) {
+	installFakeCredentialHelper(t)
+
+	dir := t.TempDir()
+	personalPath := writeTemp(t, dir, "personal.yaml", personalConfig)
+	orgPath := writeTemp(t, dir, "org.yaml", orgConfig)
+	configPath := personalPath + string(os.PathListSeparator) + orgPath
+
+	merged, err := ReadLocalConfig(configPath)
+	require.NoError(t, err)
+	merged.CredentialHelper = "fake"
+	require.NoError(t, WriteLocalConfig(*merged, configPath))
+
+	// Both files hold tokens before the credential helper was configured, so both must
+	// have theirs stripped, not just the primary file.
+	personalRaw, err := os.ReadFile(personalPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(personalRaw), "personal-token")
+
+	orgRaw, err := os.ReadFile(orgPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(orgRaw), "org-issued-token")
+}